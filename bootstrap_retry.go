@@ -0,0 +1,101 @@
+// Copyright (c) 2015 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ringpop
+
+import (
+	"errors"
+	"time"
+
+	"github.com/uber/ringpop-go/swim"
+)
+
+// errBootstrapCancelled is returned when stopped fires before any join
+// attempt completes, so callers never mistake "cancelled before trying"
+// for "succeeded" just because lastErr happened to still be nil.
+var errBootstrapCancelled = errors.New("ringpop: bootstrap cancelled")
+
+// bootstrapAttempt performs a single join attempt against the given options
+// and reports the joined hosts or an error.
+type bootstrapAttempt func(*swim.BootstrapOptions) ([]string, error)
+
+// bootstrapWithRetry drives the retry loop described by opts.HostsProvider,
+// opts.MaxRetries and opts.RetryBackoff around a single bootstrap attempt.
+// Bootstrap calls this before delegating to swim so that callers with a
+// HostsProvider get their seed list re-resolved on every attempt, which lets
+// a cold-starting node recover from stale seed IPs without a process
+// restart. When opts.EffectiveHostsProvider() is nil (neither HostsProvider
+// nor Discovery is set) the loop runs attempt exactly once, preserving the
+// existing behavior of Bootstrap. stopped is closed by
+// Destroy() to cancel any retries still in progress.
+func bootstrapWithRetry(opts *swim.BootstrapOptions, stopped <-chan struct{}, attempt bootstrapAttempt) ([]string, error) {
+	if opts == nil || opts.EffectiveHostsProvider() == nil {
+		return attempt(opts)
+	}
+	provider := opts.EffectiveHostsProvider()
+
+	maxAttempts := opts.MaxRetries
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	var lastErr error
+	for i := 0; i < maxAttempts; i++ {
+		select {
+		case <-stopped:
+			if lastErr == nil {
+				lastErr = errBootstrapCancelled
+			}
+			return nil, lastErr
+		default:
+		}
+
+		hosts, err := provider.Hosts()
+		if err != nil {
+			lastErr = err
+		} else {
+			attemptOpts := *opts
+			attemptOpts.Hosts = hosts
+
+			joined, joinErr := attempt(&attemptOpts)
+			if joinErr == nil {
+				return joined, nil
+			}
+			lastErr = joinErr
+		}
+
+		if i == maxAttempts-1 || opts.RetryBackoff <= 0 {
+			continue
+		}
+
+		timer := time.NewTimer(opts.RetryBackoff)
+		select {
+		case <-timer.C:
+		case <-stopped:
+			timer.Stop()
+			if lastErr == nil {
+				lastErr = errBootstrapCancelled
+			}
+			return nil, lastErr
+		}
+	}
+
+	return nil, lastErr
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2015 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ringpop
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uber/ringpop-go/swim"
+)
+
+// countingHostsProvider records how many times Hosts() was called and
+// returns a fixed seed list.
+type countingHostsProvider struct {
+	calls int32
+	hosts []string
+}
+
+func (p *countingHostsProvider) Hosts() ([]string, error) {
+	atomic.AddInt32(&p.calls, 1)
+	return p.hosts, nil
+}
+
+func TestBootstrapWithRetryNilProviderAttemptsOnce(t *testing.T) {
+	calls := 0
+	_, err := bootstrapWithRetry(&swim.BootstrapOptions{Hosts: []string{"127.0.0.1:3001"}}, nil, func(opts *swim.BootstrapOptions) ([]string, error) {
+		calls++
+		return opts.Hosts, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls, "expected exactly one attempt when HostsProvider is nil")
+}
+
+func TestBootstrapWithRetryReResolvesSeedsEachAttempt(t *testing.T) {
+	provider := &countingHostsProvider{hosts: []string{"127.0.0.1:3002"}}
+	opts := &swim.BootstrapOptions{
+		HostsProvider: provider,
+		MaxRetries:    3,
+	}
+
+	var attempts int32
+	_, err := bootstrapWithRetry(opts, nil, func(opts *swim.BootstrapOptions) ([]string, error) {
+		n := atomic.AddInt32(&attempts, 1)
+		if n < 3 {
+			return nil, errors.New("join failed")
+		}
+		return opts.Hosts, nil
+	})
+
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, attempts)
+	assert.EqualValues(t, 3, atomic.LoadInt32(&provider.calls), "seeds must be re-resolved on every attempt")
+}
+
+func TestBootstrapWithRetryStopsOnDestroy(t *testing.T) {
+	stopped := make(chan struct{})
+	close(stopped)
+
+	provider := &countingHostsProvider{hosts: []string{"127.0.0.1:3003"}}
+	_, err := bootstrapWithRetry(&swim.BootstrapOptions{
+		HostsProvider: provider,
+		MaxRetries:    5,
+		RetryBackoff:  time.Hour,
+	}, stopped, func(opts *swim.BootstrapOptions) ([]string, error) {
+		return nil, errors.New("join failed")
+	})
+
+	assert.Error(t, err)
+	assert.EqualValues(t, 0, atomic.LoadInt32(&provider.calls), "retries must stop cleanly once Destroy() fires")
+}
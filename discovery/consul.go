@@ -0,0 +1,95 @@
+// Copyright (c) 2015 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package discovery
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/uber/ringpop-go/swim"
+)
+
+// ConsulCatalog is the subset of a Consul API client used by ConsulProvider.
+// The *consul/api.Client's Health().Service(...) method can be adapted to
+// this shape with a small wrapper around the caller's existing client.
+type ConsulCatalog interface {
+	ServiceAddresses(service string, passingOnly bool) (addresses []string, index uint64, err error)
+}
+
+// ConsulProvider discovers and watches healthy instances of a named Consul
+// service by polling its catalog.
+type ConsulProvider struct {
+	Client      ConsulCatalog
+	Service     string
+	PassingOnly bool
+
+	// PollInterval bounds how long Watch waits between queries.
+	PollInterval time.Duration
+}
+
+var _ swim.DiscoveryProvider = (*ConsulProvider)(nil)
+
+// Hosts returns the addresses of the currently healthy service instances.
+func (p *ConsulProvider) Hosts() ([]string, error) {
+	addresses, _, err := p.Client.ServiceAddresses(p.Service, p.PassingOnly)
+	if err != nil {
+		return nil, fmt.Errorf("discovery: consul lookup of service %q failed: %v", p.Service, err)
+	}
+	return addresses, nil
+}
+
+// Watch queries ServiceAddresses every PollInterval and emits the addresses
+// whenever the returned index changes, until stopped is closed.
+func (p *ConsulProvider) Watch(stopped <-chan struct{}) (<-chan []string, error) {
+	interval := p.PollInterval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	updates := make(chan []string)
+	go func() {
+		defer close(updates)
+
+		var lastIndex uint64
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			addresses, index, err := p.Client.ServiceAddresses(p.Service, p.PassingOnly)
+			if err == nil && index != lastIndex {
+				lastIndex = index
+				select {
+				case updates <- addresses:
+				case <-stopped:
+					return
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case <-stopped:
+				return
+			}
+		}
+	}()
+
+	return updates, nil
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2015 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/uber/ringpop-go/swim"
+)
+
+// DNSResolver is the subset of net.Resolver used by DNSSRVProvider, so that
+// tests can substitute a fake without hitting a real resolver.
+type DNSResolver interface {
+	LookupSRV(ctx context.Context, service, proto, name string) (cname string, addrs []*net.SRV, err error)
+}
+
+// DNSSRVProvider discovers seeds via a DNS SRV record, e.g. the headless
+// service record a Kubernetes StatefulSet publishes for its pods.
+type DNSSRVProvider struct {
+	Resolver DNSResolver
+	Service  string
+	Proto    string
+	Name     string
+
+	// PollInterval controls how often Watch re-queries the SRV record.
+	PollInterval time.Duration
+}
+
+var _ swim.DiscoveryProvider = (*DNSSRVProvider)(nil)
+
+// Hosts resolves the configured SRV record into a list of "host:port"
+// seeds.
+func (p *DNSSRVProvider) Hosts() ([]string, error) {
+	resolver := p.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	_, srvs, err := resolver.LookupSRV(context.Background(), p.Service, p.Proto, p.Name)
+	if err != nil {
+		return nil, fmt.Errorf("discovery: dns srv lookup of %s failed: %v", p.Name, err)
+	}
+
+	hosts := make([]string, 0, len(srvs))
+	for _, srv := range srvs {
+		hosts = append(hosts, fmt.Sprintf("%s:%d", trimTrailingDot(srv.Target), srv.Port))
+	}
+	return hosts, nil
+}
+
+// Watch polls the SRV record every PollInterval and emits the resolved host
+// list whenever it changes, until stopped is closed.
+func (p *DNSSRVProvider) Watch(stopped <-chan struct{}) (<-chan []string, error) {
+	interval := p.PollInterval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	updates := make(chan []string)
+	go func() {
+		defer close(updates)
+
+		var last string
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			hosts, err := p.Hosts()
+			if err == nil {
+				if key := fmt.Sprint(hosts); key != last {
+					last = key
+					select {
+					case updates <- hosts:
+					case <-stopped:
+						return
+					}
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case <-stopped:
+				return
+			}
+		}
+	}()
+
+	return updates, nil
+}
+
+func trimTrailingDot(host string) string {
+	if n := len(host); n > 0 && host[n-1] == '.' {
+		return host[:n-1]
+	}
+	return host
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2015 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package discovery
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeDNSResolver struct {
+	srvs []*net.SRV
+}
+
+func (r *fakeDNSResolver) LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	return "", r.srvs, nil
+}
+
+func TestDNSSRVProviderHosts(t *testing.T) {
+	provider := &DNSSRVProvider{
+		Resolver: &fakeDNSResolver{srvs: []*net.SRV{
+			{Target: "pod-0.test.default.svc.cluster.local.", Port: 3001},
+			{Target: "pod-1.test.default.svc.cluster.local.", Port: 3001},
+		}},
+		Name: "test",
+	}
+
+	hosts, err := provider.Hosts()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"pod-0.test.default.svc.cluster.local:3001",
+		"pod-1.test.default.svc.cluster.local:3001",
+	}, hosts)
+}
+
+func TestDNSSRVProviderWatchEmitsOnChange(t *testing.T) {
+	resolver := &fakeDNSResolver{srvs: []*net.SRV{{Target: "pod-0.test.", Port: 3001}}}
+	provider := &DNSSRVProvider{Resolver: resolver, Name: "test", PollInterval: 5 * time.Millisecond}
+
+	stopped := make(chan struct{})
+	defer close(stopped)
+
+	updates, err := provider.Watch(stopped)
+	assert.NoError(t, err)
+
+	select {
+	case hosts := <-updates:
+		assert.Equal(t, []string{"pod-0.test:3001"}, hosts)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for initial watch update")
+	}
+
+	resolver.srvs = append(resolver.srvs, &net.SRV{Target: "pod-1.test.", Port: 3001})
+
+	select {
+	case hosts := <-updates:
+		assert.Len(t, hosts, 2)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for watch update after change")
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2015 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package discovery
+
+import (
+	"context"
+
+	"github.com/uber/ringpop-go/swim"
+)
+
+// EtcdKV is the subset of etcd client behavior used by EtcdProvider. Callers
+// adapt their *clientv3.Client to this interface with a small wrapper, so
+// ringpop-go does not depend on the etcd client module.
+type EtcdKV interface {
+	Get(ctx context.Context, key string) (values []string, err error)
+	Watch(ctx context.Context, key string) (updates <-chan []string)
+}
+
+// EtcdProvider discovers and watches seeds stored under a single etcd key,
+// e.g. a key whose value is kept up to date by a sidecar that mirrors pod
+// membership.
+type EtcdProvider struct {
+	Client EtcdKV
+	Key    string
+}
+
+var _ swim.DiscoveryProvider = (*EtcdProvider)(nil)
+
+// Hosts reads the current seed list from etcd.
+func (p *EtcdProvider) Hosts() ([]string, error) {
+	return p.Client.Get(context.Background(), p.Key)
+}
+
+// Watch streams seed list updates for Key until stopped is closed.
+func (p *EtcdProvider) Watch(stopped <-chan struct{}) (<-chan []string, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-stopped
+		cancel()
+	}()
+
+	return p.Client.Watch(ctx, p.Key), nil
+}
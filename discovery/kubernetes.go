@@ -0,0 +1,109 @@
+// Copyright (c) 2015 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package discovery
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/uber/ringpop-go/swim"
+)
+
+// EndpointsLister is the subset of a Kubernetes clientset used by
+// KubernetesProvider, e.g. clientset.CoreV1().Endpoints(namespace) adapted
+// to this shape, to list and watch ready pod IPs for a Service without
+// ringpop-go depending on client-go directly.
+type EndpointsLister interface {
+	ReadyAddresses(namespace, service string) (addresses []string, resourceVersion string, err error)
+}
+
+// KubernetesProvider discovers and watches the ready pod IPs backing a
+// Kubernetes Service's Endpoints object.
+type KubernetesProvider struct {
+	Client    EndpointsLister
+	Namespace string
+	Service   string
+	Port      int
+
+	// PollInterval controls how often Watch re-lists Endpoints.
+	PollInterval time.Duration
+}
+
+var _ swim.DiscoveryProvider = (*KubernetesProvider)(nil)
+
+// Hosts returns "ip:port" seeds for every ready address backing Service.
+func (p *KubernetesProvider) Hosts() ([]string, error) {
+	addresses, _, err := p.Client.ReadyAddresses(p.Namespace, p.Service)
+	if err != nil {
+		return nil, fmt.Errorf("discovery: k8s endpoints lookup of %s/%s failed: %v", p.Namespace, p.Service, err)
+	}
+
+	hosts := make([]string, 0, len(addresses))
+	for _, addr := range addresses {
+		hosts = append(hosts, fmt.Sprintf("%s:%d", addr, p.Port))
+	}
+	return hosts, nil
+}
+
+// Watch re-lists Endpoints every PollInterval and emits the ready hosts
+// whenever the resourceVersion changes, until stopped is closed. Polling
+// rather than a long-lived watch keeps this provider usable
+// against any client satisfying EndpointsLister, including one backed by a
+// plain informer cache.
+func (p *KubernetesProvider) Watch(stopped <-chan struct{}) (<-chan []string, error) {
+	interval := p.PollInterval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	updates := make(chan []string)
+	go func() {
+		defer close(updates)
+
+		var lastVersion string
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			addresses, version, err := p.Client.ReadyAddresses(p.Namespace, p.Service)
+			if err == nil && version != lastVersion {
+				lastVersion = version
+				hosts := make([]string, 0, len(addresses))
+				for _, addr := range addresses {
+					hosts = append(hosts, fmt.Sprintf("%s:%d", addr, p.Port))
+				}
+				select {
+				case updates <- hosts:
+				case <-stopped:
+					return
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case <-stopped:
+				return
+			}
+		}
+	}()
+
+	return updates, nil
+}
@@ -0,0 +1,267 @@
+// Copyright (c) 2015 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ringpop
+
+import (
+	"sync"
+	"time"
+)
+
+// Event is implemented by every structured event published through
+// Ringpop.Events() and Subscribe(). It is sealed: only the variants in this
+// file implement it, so a type switch over Event can be treated as
+// exhaustive. Ringpop.HandleEvent remains the entry point for swim's
+// untyped events; for the variants below it translates the swim event into
+// the matching Event and publishes it here, in addition to the existing
+// per-listener dispatch, so existing RegisterListener callers keep working
+// unchanged. swim.JoinReceiveEvent, JoinCompleteEvent, PingSendEvent,
+// PingReceiveEvent, PingRequestsSendEvent and PingRequestReceiveEvent have
+// no typed counterpart and are only dispatched to Listeners.
+type Event interface {
+	isRingpopEvent()
+}
+
+// MembershipChanged reports the net effect of a
+// swim.MemberlistChangesAppliedEvent: which addresses became alive, faulty,
+// suspect, or left.
+type MembershipChanged struct {
+	Alive   []string
+	Faulty  []string
+	Suspect []string
+	Leave   []string
+}
+
+func (MembershipChanged) isRingpopEvent() {}
+
+// ChecksumComputed reports a new hash ring checksum, emitted alongside
+// MembershipChanged whenever the ring recomputes it.
+type ChecksumComputed struct {
+	Checksum uint32
+}
+
+func (ChecksumComputed) isRingpopEvent() {}
+
+// RingChanged reports that the hash ring's server set changed.
+type RingChanged struct {
+	ServersAdded   []string
+	ServersRemoved []string
+}
+
+func (RingChanged) isRingpopEvent() {}
+
+// PingLatency reports the observed round-trip time of a relayed ping this
+// node issued for a ping-req (swim.PingRequestPingEvent). Direct pings are
+// not reported as PingLatency.
+type PingLatency struct {
+	Target   string
+	Duration time.Duration
+}
+
+func (PingLatency) isRingpopEvent() {}
+
+// MaxPAdjusted reports a change to swim's piggyback count.
+type MaxPAdjusted struct {
+	NewPCount int
+}
+
+func (MaxPAdjusted) isRingpopEvent() {}
+
+// EventFilter decides whether a Subscription should receive an Event. A nil
+// filter matches every event.
+type EventFilter func(Event) bool
+
+// EventBackpressure controls what a Subscription does when its buffer
+// fills up.
+type EventBackpressure int
+
+const (
+	// DropOldest discards the oldest buffered event to make room for the
+	// new one. This is the default, and is appropriate for dashboards and
+	// metrics forwarding where the latest state matters more than a
+	// complete history.
+	DropOldest EventBackpressure = iota
+	// Block makes publish wait for this subscriber to make room before
+	// moving on to the next one. Use this only for subscribers that must
+	// not miss an event: a slow Block subscriber delays delivery to every
+	// subscriber behind it in that publish call, and delays its own next
+	// delivery until it catches up.
+	Block
+)
+
+// defaultEventBufferSize is the buffer size used when
+// SubscribeOptions.BufferSize is zero.
+const defaultEventBufferSize = 64
+
+// SubscribeOptions configures a call to Subscribe.
+type SubscribeOptions struct {
+	// Filter, if set, restricts the subscription to events it returns true
+	// for.
+	Filter EventFilter
+	// BufferSize is the subscription channel's buffer size. Zero means
+	// defaultEventBufferSize.
+	BufferSize int
+	// Backpressure controls behavior when the buffer is full.
+	Backpressure EventBackpressure
+}
+
+// Subscription is a live registration returned by Subscribe. Events matching
+// the subscription's filter are delivered on C until Close is called.
+type Subscription struct {
+	C <-chan Event
+
+	bus *eventBus
+	sub *eventSub
+}
+
+// Close unregisters the subscription. It is safe to call more than once.
+func (s Subscription) Close() {
+	s.bus.unsubscribe(s.sub)
+}
+
+// eventSub owns its own mutex, separate from eventBus.mu, so that delivering
+// to (or closing) one subscriber never holds up the bus-wide lock that
+// subscribe/unsubscribe and delivery to every other subscriber need.
+type eventSub struct {
+	mu           sync.Mutex
+	ch           chan Event
+	closed       bool
+	filter       EventFilter
+	backpressure EventBackpressure
+}
+
+// deliver sends ev to the subscription according to its backpressure
+// policy. It is a no-op once the subscription has been closed.
+func (s *eventSub) deliver(ev Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	switch s.backpressure {
+	case Block:
+		s.ch <- ev
+	default: // DropOldest
+		for {
+			select {
+			case s.ch <- ev:
+			default:
+				select {
+				case <-s.ch:
+				default:
+				}
+				continue
+			}
+			break
+		}
+	}
+}
+
+// close marks the subscription closed and closes its channel. It is safe to
+// call more than once.
+func (s *eventSub) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+	s.closed = true
+	close(s.ch)
+}
+
+// eventBus fans typed Events out to subscribers, including the channel
+// backing Ringpop.Events().
+type eventBus struct {
+	mu   sync.Mutex
+	subs map[*eventSub]struct{}
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{subs: make(map[*eventSub]struct{})}
+}
+
+func (b *eventBus) subscribe(opts SubscribeOptions) Subscription {
+	bufSize := opts.BufferSize
+	if bufSize <= 0 {
+		bufSize = defaultEventBufferSize
+	}
+
+	sub := &eventSub{
+		ch:           make(chan Event, bufSize),
+		filter:       opts.Filter,
+		backpressure: opts.Backpressure,
+	}
+
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+
+	return Subscription{C: sub.ch, bus: b, sub: sub}
+}
+
+func (b *eventBus) unsubscribe(sub *eventSub) {
+	b.mu.Lock()
+	_, ok := b.subs[sub]
+	delete(b.subs, sub)
+	b.mu.Unlock()
+
+	if ok {
+		sub.close()
+	}
+}
+
+// publish fans ev out to every matching subscriber, honoring each
+// subscription's backpressure policy. It only holds the bus-wide lock long
+// enough to snapshot the current subscribers, so a Block subscriber that
+// can't keep up never stalls subscribe/unsubscribe. Delivery is sequential:
+// such a subscriber does delay delivery to the subscribers after it in the
+// same publish call.
+func (b *eventBus) publish(ev Event) {
+	b.mu.Lock()
+	subs := make([]*eventSub, 0, len(b.subs))
+	for sub := range b.subs {
+		subs = append(subs, sub)
+	}
+	b.mu.Unlock()
+
+	for _, sub := range subs {
+		if sub.filter != nil && !sub.filter(ev) {
+			continue
+		}
+		sub.deliver(ev)
+	}
+}
+
+// Events returns a channel of every typed Event Ringpop publishes, with a
+// default buffer and drop-oldest backpressure. It is equivalent to
+// Subscribe(SubscribeOptions{}).
+func (rp *Ringpop) Events() <-chan Event {
+	return rp.Subscribe(SubscribeOptions{}).C
+}
+
+// Subscribe registers a new Subscription filtered and buffered according to
+// opts. Callers that need custom filtering or backpressure should use this
+// directly instead of Events().
+func (rp *Ringpop) Subscribe(opts SubscribeOptions) Subscription {
+	return rp.events.subscribe(opts)
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2015 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ringpop
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uber/ringpop-go/swim"
+	"github.com/uber/tchannel-go"
+)
+
+// TestHandleEventPublishesTypedEvents demonstrates the translation
+// HandleEvent's doc comment promises: real swim events fed through a live
+// Ringpop's HandleEvent arrive on Events() as their typed Event
+// counterparts, alongside (not instead of) the legacy Listener dispatch.
+func TestHandleEventPublishesTypedEvents(t *testing.T) {
+	ch, err := tchannel.NewChannel("test", nil)
+	assert.NoError(t, err)
+	defer ch.Close()
+
+	rp, err := New("test", Identity("127.0.0.1:3001"), Channel(ch))
+	assert.NoError(t, err)
+	defer rp.Destroy()
+
+	sub := rp.Subscribe(SubscribeOptions{BufferSize: 8})
+	defer sub.Close()
+
+	rp.HandleEvent(swim.MemberlistChangesAppliedEvent{
+		Changes: []swim.Change{
+			{Address: "127.0.0.1:3002", Status: swim.Alive},
+			{Address: "127.0.0.1:3003", Status: swim.Suspect},
+		},
+	})
+	rp.HandleEvent(swim.MaxPAdjustedEvent{NewPCount: 5})
+
+	select {
+	case ev := <-sub.C:
+		assert.Equal(t, MembershipChanged{Alive: []string{"127.0.0.1:3002"}, Suspect: []string{"127.0.0.1:3003"}}, ev)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for MembershipChanged")
+	}
+
+	select {
+	case ev := <-sub.C:
+		assert.Equal(t, ChecksumComputed{Checksum: rp.ring.Checksum()}, ev)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for ChecksumComputed")
+	}
+
+	select {
+	case ev := <-sub.C:
+		assert.Equal(t, RingChanged{ServersAdded: []string{"127.0.0.1:3002"}}, ev)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for RingChanged")
+	}
+
+	select {
+	case ev := <-sub.C:
+		assert.Equal(t, MaxPAdjusted{NewPCount: 5}, ev)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for MaxPAdjusted")
+	}
+}
+
+// TestEventBusBlockSubscriberDoesNotStallBusOperations tests that a stuck
+// Block subscriber's publish call, which is necessarily blocked mid-send on
+// that one subscription, does not hold the bus-wide lock — so subscribing
+// and unsubscribing other subscriptions keeps working concurrently. Closing
+// the stuck subscription itself is a separate guarantee (it must not race a
+// concurrent send) and isn't what this test exercises.
+func TestEventBusBlockSubscriberDoesNotStallBusOperations(t *testing.T) {
+	bus := newEventBus()
+
+	stuck := bus.subscribe(SubscribeOptions{BufferSize: 1, Backpressure: Block})
+	defer stuck.Close()
+	idle := bus.subscribe(SubscribeOptions{BufferSize: 1})
+
+	// Fill stuck's buffer so the next publish to it blocks.
+	bus.publish(MaxPAdjusted{NewPCount: 1})
+	<-idle.C
+
+	go bus.publish(MaxPAdjusted{NewPCount: 2})
+	// Give the publish goroutine a moment to actually reach the blocking
+	// send on stuck before we exercise the bus-wide lock.
+	time.Sleep(10 * time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		extra := bus.subscribe(SubscribeOptions{})
+		extra.Close()
+		idle.Close()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("subscribe/unsubscribe must not be blocked by another subscriber's stuck publish")
+	}
+
+	<-stuck.C // unblock the publish goroutine so it doesn't leak
+}
+
+func TestEventBusPublishDeliversToAllSubscribers(t *testing.T) {
+	bus := newEventBus()
+
+	sub1 := bus.subscribe(SubscribeOptions{})
+	sub2 := bus.subscribe(SubscribeOptions{})
+	defer sub1.Close()
+	defer sub2.Close()
+
+	bus.publish(MaxPAdjusted{NewPCount: 100})
+
+	assert.Equal(t, MaxPAdjusted{NewPCount: 100}, <-sub1.C)
+	assert.Equal(t, MaxPAdjusted{NewPCount: 100}, <-sub2.C)
+}
+
+func TestEventBusFilterExcludesNonMatchingEvents(t *testing.T) {
+	bus := newEventBus()
+
+	sub := bus.subscribe(SubscribeOptions{
+		Filter: func(ev Event) bool {
+			_, ok := ev.(RingChanged)
+			return ok
+		},
+	})
+	defer sub.Close()
+
+	bus.publish(MaxPAdjusted{NewPCount: 1})
+	bus.publish(RingChanged{ServersAdded: []string{"127.0.0.1:3001"}})
+
+	ev := <-sub.C
+	assert.Equal(t, RingChanged{ServersAdded: []string{"127.0.0.1:3001"}}, ev)
+}
+
+func TestEventBusDropOldestDoesNotBlockPublisher(t *testing.T) {
+	bus := newEventBus()
+	sub := bus.subscribe(SubscribeOptions{BufferSize: 1, Backpressure: DropOldest})
+	defer sub.Close()
+
+	bus.publish(MaxPAdjusted{NewPCount: 1})
+	bus.publish(MaxPAdjusted{NewPCount: 2})
+
+	assert.Equal(t, MaxPAdjusted{NewPCount: 2}, <-sub.C, "newest event should survive a full drop-oldest buffer")
+}
+
+func TestEventBusCloseStopsDelivery(t *testing.T) {
+	bus := newEventBus()
+	sub := bus.subscribe(SubscribeOptions{})
+
+	sub.Close()
+	bus.publish(MaxPAdjusted{NewPCount: 1})
+
+	_, ok := <-sub.C
+	assert.False(t, ok, "channel must be closed once the subscription is closed")
+}
@@ -0,0 +1,249 @@
+// Copyright (c) 2015 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ringpop
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// LifecycleState mirrors the created/initialized/ready/destroyed states a
+// Ringpop instance moves through, surfaced as an explicit, observable type
+// instead of the bare state field those four constants were checked
+// against directly.
+type LifecycleState int
+
+const (
+	// LifecycleCreated is the state of a Ringpop instance that has not yet
+	// called init().
+	LifecycleCreated LifecycleState = iota
+	// LifecycleInitialized is the state of a Ringpop instance that has
+	// called init() but not yet completed a successful Bootstrap.
+	LifecycleInitialized
+	// LifecycleReady is the state of a Ringpop instance that has completed
+	// a successful Bootstrap.
+	LifecycleReady
+	// LifecycleDestroyed is the terminal state entered by Destroy().
+	LifecycleDestroyed
+)
+
+func (s LifecycleState) String() string {
+	switch s {
+	case LifecycleCreated:
+		return "created"
+	case LifecycleInitialized:
+		return "initialized"
+	case LifecycleReady:
+		return "ready"
+	case LifecycleDestroyed:
+		return "destroyed"
+	default:
+		return fmt.Sprintf("LifecycleState(%d)", int(s))
+	}
+}
+
+// LifecycleHook runs around a start or stop transition. A non-nil error from
+// a pre-hook aborts the transition.
+type LifecycleHook func() error
+
+// LifecycleStatus is a point-in-time snapshot of a Lifecycle, returned by
+// Lifecycle.Status().
+type LifecycleStatus struct {
+	State          LifecycleState
+	Uptime         time.Duration
+	LastTransition time.Time
+	LastError      error
+}
+
+// Lifecycle tracks the created/initialized/ready/destroyed progression of a
+// Ringpop instance, exposing pre/post start and stop hooks plus an event
+// channel and status snapshot so that embedding services can integrate
+// Ringpop into their own service-container startup ordering and drain
+// traffic before calling Destroy(), instead of polling Ready() in a loop.
+type Lifecycle struct {
+	mu sync.Mutex
+
+	state          LifecycleState
+	readyAt        time.Time
+	lastTransition time.Time
+	lastErr        error
+
+	preStart  []LifecycleHook
+	postStart []LifecycleHook
+	preStop   []LifecycleHook
+	postStop  []LifecycleHook
+
+	changed chan LifecycleState
+}
+
+// NewLifecycle returns a Lifecycle in the LifecycleCreated state.
+func NewLifecycle() *Lifecycle {
+	return &Lifecycle{
+		state:          LifecycleCreated,
+		lastTransition: time.Now(),
+		// Buffered so that Transition never blocks on a caller who isn't
+		// currently reading StateChanged.
+		changed: make(chan LifecycleState, 8),
+	}
+}
+
+// AddPreStartHook registers a hook run before a transition into
+// LifecycleReady. A non-nil error aborts the transition.
+func (l *Lifecycle) AddPreStartHook(hook LifecycleHook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.preStart = append(l.preStart, hook)
+}
+
+// AddPostStartHook registers a hook run after a successful transition into
+// LifecycleReady. Errors are recorded via Status().LastError but do not
+// reverse the transition.
+func (l *Lifecycle) AddPostStartHook(hook LifecycleHook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.postStart = append(l.postStart, hook)
+}
+
+// AddPreStopHook registers a hook run before a transition into
+// LifecycleDestroyed, e.g. to drain in-flight traffic.
+func (l *Lifecycle) AddPreStopHook(hook LifecycleHook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.preStop = append(l.preStop, hook)
+}
+
+// AddPostStopHook registers a hook run after a Ringpop instance has reached
+// LifecycleDestroyed.
+func (l *Lifecycle) AddPostStopHook(hook LifecycleHook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.postStop = append(l.postStop, hook)
+}
+
+// StateChanged returns a channel of states this Lifecycle transitions
+// through. It is created with a small buffer; a consumer that falls behind
+// will not block Transition, but may miss intermediate states and should
+// call Status() for the current one.
+func (l *Lifecycle) StateChanged() <-chan LifecycleState {
+	return l.changed
+}
+
+// WaitFor blocks until the Lifecycle reaches the given state or ctx is
+// done, whichever happens first.
+func (l *Lifecycle) WaitFor(ctx context.Context, state LifecycleState) error {
+	if l.currentState() == state {
+		return nil
+	}
+
+	for {
+		select {
+		case s := <-l.changed:
+			if s == state {
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Status returns a snapshot of the Lifecycle's current state.
+func (l *Lifecycle) Status() LifecycleStatus {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var uptime time.Duration
+	if l.state == LifecycleReady && !l.readyAt.IsZero() {
+		uptime = time.Since(l.readyAt)
+	}
+
+	return LifecycleStatus{
+		State:          l.state,
+		Uptime:         uptime,
+		LastTransition: l.lastTransition,
+		LastError:      l.lastErr,
+	}
+}
+
+func (l *Lifecycle) currentState() LifecycleState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.state
+}
+
+// transition moves the Lifecycle to state, running the appropriate pre/post
+// hooks around the change. Pre-hooks only run for the start (->
+// LifecycleReady) and stop (-> LifecycleDestroyed) transitions; a pre-hook
+// error aborts the transition and is recorded as LastError.
+func (l *Lifecycle) transition(state LifecycleState) error {
+	var pre, post []LifecycleHook
+	switch state {
+	case LifecycleReady:
+		pre, post = l.preStart, l.postStart
+	case LifecycleDestroyed:
+		pre, post = l.preStop, l.postStop
+	}
+
+	for _, hook := range pre {
+		if err := hook(); err != nil {
+			l.mu.Lock()
+			l.lastErr = err
+			l.mu.Unlock()
+			return err
+		}
+	}
+
+	l.mu.Lock()
+	l.state = state
+	l.lastTransition = time.Now()
+	if state == LifecycleReady {
+		l.readyAt = l.lastTransition
+	}
+	l.mu.Unlock()
+
+	for {
+		select {
+		case l.changed <- state:
+		default:
+			// Buffer full; drop the oldest notification to make room, so
+			// the newest state is always delivered. Status() still reports
+			// the current state to a consumer that fell behind.
+			select {
+			case <-l.changed:
+			default:
+			}
+			continue
+		}
+		break
+	}
+
+	for _, hook := range post {
+		if err := hook(); err != nil {
+			l.mu.Lock()
+			l.lastErr = err
+			l.mu.Unlock()
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2015 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ringpop
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLifecycleStartsCreated(t *testing.T) {
+	l := NewLifecycle()
+	assert.Equal(t, LifecycleCreated, l.Status().State)
+}
+
+func TestLifecycleTransitionRunsHooksInOrder(t *testing.T) {
+	l := NewLifecycle()
+
+	var order []string
+	l.AddPreStartHook(func() error {
+		order = append(order, "pre")
+		return nil
+	})
+	l.AddPostStartHook(func() error {
+		order = append(order, "post")
+		return nil
+	})
+
+	assert.NoError(t, l.transition(LifecycleReady))
+	assert.Equal(t, []string{"pre", "post"}, order)
+	assert.Equal(t, LifecycleReady, l.Status().State)
+	assert.NotZero(t, l.Status().Uptime)
+}
+
+func TestLifecyclePreStartHookErrorAbortsTransition(t *testing.T) {
+	l := NewLifecycle()
+	boom := errors.New("boom")
+
+	ran := false
+	l.AddPreStartHook(func() error { return boom })
+	l.AddPostStartHook(func() error { ran = true; return nil })
+
+	err := l.transition(LifecycleReady)
+	assert.Equal(t, boom, err)
+	assert.False(t, ran, "post-start hook must not run when a pre-start hook fails")
+	assert.Equal(t, LifecycleCreated, l.Status().State)
+	assert.Equal(t, boom, l.Status().LastError)
+}
+
+func TestLifecycleStateChangedEmitsTransitions(t *testing.T) {
+	l := NewLifecycle()
+
+	go func() {
+		l.transition(LifecycleInitialized)
+		l.transition(LifecycleReady)
+	}()
+
+	assert.Equal(t, LifecycleInitialized, <-l.StateChanged())
+	assert.Equal(t, LifecycleReady, <-l.StateChanged())
+}
+
+func TestLifecycleWaitForReturnsImmediatelyWhenAlreadyInState(t *testing.T) {
+	l := NewLifecycle()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	assert.NoError(t, l.WaitFor(ctx, LifecycleCreated))
+}
+
+func TestLifecycleWaitForBlocksUntilTransition(t *testing.T) {
+	l := NewLifecycle()
+
+	done := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		done <- l.WaitFor(ctx, LifecycleReady)
+	}()
+
+	l.transition(LifecycleInitialized)
+	l.transition(LifecycleReady)
+
+	assert.NoError(t, <-done)
+}
+
+func TestLifecycleWaitForTimesOut(t *testing.T) {
+	l := NewLifecycle()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+
+	err := l.WaitFor(ctx, LifecycleReady)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
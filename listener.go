@@ -0,0 +1,56 @@
+// Copyright (c) 2015 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ringpop
+
+import "sync"
+
+// Listener receives every event HandleEvent processes, untyped, in
+// dispatch order. It predates the typed Events()/Subscribe() API and is
+// kept as a thin adapter for callers who haven't migrated.
+type Listener interface {
+	HandleEvent(event interface{})
+}
+
+// listenerSet is a concurrency-safe set of registered Listeners.
+type listenerSet struct {
+	mu        sync.RWMutex
+	listeners []Listener
+}
+
+func (l *listenerSet) Register(listener Listener) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.listeners = append(l.listeners, listener)
+}
+
+func (l *listenerSet) Dispatch(event interface{}) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for _, listener := range l.listeners {
+		listener.HandleEvent(event)
+	}
+}
+
+// RegisterListener registers listener to receive every event HandleEvent
+// processes.
+func (rp *Ringpop) RegisterListener(listener Listener) {
+	rp.listeners.Register(listener)
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2015 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ringpop
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/uber/ringpop-go/stats"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Metrics configures Ringpop to report its stats to registerer as
+// Prometheus metrics, instead of the StatsD-style dot-delimited keys
+// statter produces by default. The statter is wired up once Identity is
+// known, since metrics are labeled by instance rather than having the
+// address baked into the metric name.
+func Metrics(registerer prometheus.Registerer) Option {
+	return func(options *Options) {
+		options.statterFactory = func(identity string) Statter {
+			return stats.NewPrometheusStatter(registerer, identity)
+		}
+	}
+}
+
+// Meter configures Ringpop to report its stats through an OpenTelemetry
+// Meter, instead of the StatsD-style dot-delimited keys statter produces by
+// default.
+func Meter(meter metric.Meter) Option {
+	return func(options *Options) {
+		options.statterFactory = func(identity string) Statter {
+			return stats.NewOTelMeterStatter(meter, identity)
+		}
+	}
+}
+
+// Statter is the stats sink Ringpop writes counters, gauges, and timers to.
+// It matches stats.Reporter so that either adapter in the stats package can
+// be used here directly.
+type Statter = stats.Reporter
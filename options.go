@@ -0,0 +1,57 @@
+// Copyright (c) 2015 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ringpop
+
+import (
+	"github.com/uber/ringpop-go/swim"
+	"github.com/uber/tchannel-go"
+)
+
+// Options collects the configuration gathered from the Option values passed
+// to New.
+type Options struct {
+	identity string
+	channel  *tchannel.Channel
+
+	discoveryProvider swim.DiscoveryProvider
+	statterFactory    func(identity string) Statter
+	tlsConfigProvider TLSConfigProvider
+}
+
+// Option configures a Ringpop instance at construction time.
+type Option func(*Options)
+
+// Identity sets the address other nodes use to reach this one, e.g.
+// "127.0.0.1:3001". If not set, New falls back to the Channel's own
+// PeerInfo().HostPort.
+func Identity(identity string) Option {
+	return func(options *Options) {
+		options.identity = identity
+	}
+}
+
+// Channel sets the tchannel.Channel Ringpop sends and receives its swim and
+// forwarding RPCs over.
+func Channel(channel *tchannel.Channel) Option {
+	return func(options *Options) {
+		options.channel = channel
+	}
+}
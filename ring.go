@@ -0,0 +1,129 @@
+// Copyright (c) 2015 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ringpop
+
+import (
+	"hash/crc32"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ring is Ringpop's membership view: the set of servers currently
+// considered alive, along with the lookups used to shard keys across them.
+type ring struct {
+	mu      sync.RWMutex
+	servers map[string]struct{}
+}
+
+func newRing() *ring {
+	return &ring{servers: make(map[string]struct{})}
+}
+
+// AddServer adds a server to the ring. It is a no-op if already present.
+func (r *ring) AddServer(server string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.servers[server] = struct{}{}
+}
+
+// AddServers adds every server in servers to the ring.
+func (r *ring) AddServers(servers []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range servers {
+		r.servers[s] = struct{}{}
+	}
+}
+
+// RemoveServer removes a server from the ring. It is a no-op if absent.
+func (r *ring) RemoveServer(server string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.servers, server)
+}
+
+// HasServer reports whether server is currently in the ring.
+func (r *ring) HasServer(server string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.servers[server]
+	return ok
+}
+
+// GetServers returns every server currently in the ring, sorted for a
+// deterministic order.
+func (r *ring) GetServers() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	servers := make([]string, 0, len(r.servers))
+	for s := range r.servers {
+		servers = append(servers, s)
+	}
+	sort.Strings(servers)
+	return servers
+}
+
+// Checksum returns a hash of the current server set, changing whenever
+// membership changes.
+func (r *ring) Checksum() uint32 {
+	servers := r.GetServers()
+	return crc32.ChecksumIEEE([]byte(strings.Join(servers, ",")))
+}
+
+// Lookup returns the server responsible for key. ok is false if the ring
+// has no servers.
+func (r *ring) Lookup(key string) (server string, ok bool) {
+	servers := r.GetServers()
+	if len(servers) == 0 {
+		return "", false
+	}
+
+	idx := int(crc32.ChecksumIEEE([]byte(key))) % len(servers)
+	if idx < 0 {
+		idx += len(servers)
+	}
+	return servers[idx], true
+}
+
+// LookupN returns up to n distinct servers responsible for key, in ring
+// order starting from the same server Lookup would return.
+func (r *ring) LookupN(key string, n int) []string {
+	servers := r.GetServers()
+	if len(servers) == 0 || n <= 0 {
+		return nil
+	}
+	if n > len(servers) {
+		n = len(servers)
+	}
+
+	start := int(crc32.ChecksumIEEE([]byte(key))) % len(servers)
+	if start < 0 {
+		start += len(servers)
+	}
+
+	result := make([]string, n)
+	for i := 0; i < n; i++ {
+		result[i] = servers[(start+i)%len(servers)]
+	}
+	return result
+}
@@ -0,0 +1,510 @@
+// Copyright (c) 2015 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ringpop
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/uber/ringpop-go/swim"
+	"github.com/uber/tchannel-go"
+)
+
+// Interface is the public surface of a Ringpop instance, split out mainly
+// so tests can substitute a mock for it.
+type Interface interface {
+	App() string
+	WhoAmI() (string, error)
+	Uptime() (time.Duration, error)
+	Checksum() (uint32, error)
+	Bootstrap(opts *swim.BootstrapOptions) ([]string, error)
+	Destroy()
+	Ready() bool
+	Lookup(key string) (string, error)
+	LookupN(key string, n int) ([]string, error)
+	GetReachableMembers() ([]string, error)
+	HandleEvent(event interface{})
+	RegisterListener(listener Listener)
+}
+
+// Ringpop is a library that manages cluster membership and forwards
+// requests to the right node in the cluster, using a consistent hash ring
+// built from swim's membership view.
+type Ringpop struct {
+	mu sync.RWMutex
+
+	app      string
+	identity string
+	channel  *tchannel.Channel
+	node     *swim.Node
+
+	state   state
+	readyAt time.Time
+
+	ring      *ring
+	statter   Statter
+	listeners listenerSet
+
+	discoveryProvider swim.DiscoveryProvider
+	watchingDiscovery bool
+
+	lifecycle *Lifecycle
+	events    *eventBus
+
+	destroyed chan struct{}
+}
+
+var _ Interface = (*Ringpop)(nil)
+
+// New creates a new Ringpop instance.
+func New(app string, opts ...Option) (*Ringpop, error) {
+	if app == "" {
+		return nil, errors.New("ringpop: app name is required")
+	}
+
+	options := &Options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.channel == nil {
+		return nil, errors.New("ringpop: a Channel option is required")
+	}
+
+	identity := options.identity
+	if identity == "" {
+		identity = options.channel.PeerInfo().HostPort
+	}
+
+	rp := &Ringpop{
+		app:               app,
+		identity:          identity,
+		channel:           options.channel,
+		node:              swim.NewNode(identity),
+		state:             created,
+		ring:              newRing(),
+		statter:           noopStatter{},
+		discoveryProvider: options.discoveryProvider,
+		lifecycle:         NewLifecycle(),
+		events:            newEventBus(),
+		destroyed:         make(chan struct{}),
+	}
+
+	if options.statterFactory != nil {
+		rp.statter = options.statterFactory(identity)
+	}
+
+	if options.tlsConfigProvider != nil {
+		rp.node.Dial = tlsDialer(options.tlsConfigProvider)
+	}
+
+	return rp, nil
+}
+
+// init moves a freshly created Ringpop into the initialized state. It is
+// idempotent; calling it more than once, or after Bootstrap has already
+// moved past initialized, is a no-op.
+func (rp *Ringpop) init() {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	rp.initLocked()
+}
+
+func (rp *Ringpop) initLocked() {
+	if rp.state == created {
+		// initialized has no pre-hooks, so this can never fail.
+		_ = rp.setStateLocked(initialized)
+	}
+}
+
+// setStateLocked updates the coarse state field and drives the matching
+// Lifecycle transition. The only transitions that can fail are into ready
+// and destroyed, where a registered pre-hook returned an error; callers that
+// care must check the returned error, since rp.state is left unchanged when
+// that happens.
+func (rp *Ringpop) setStateLocked(s state) error {
+	if err := rp.lifecycle.transition(lifecycleStateFor(s)); err != nil {
+		return err
+	}
+
+	rp.state = s
+	if s == ready {
+		rp.readyAt = time.Now()
+	}
+	return nil
+}
+
+// lifecycleStateFor maps the unexported state enum to its public
+// LifecycleState counterpart.
+func lifecycleStateFor(s state) LifecycleState {
+	switch s {
+	case created:
+		return LifecycleCreated
+	case initialized:
+		return LifecycleInitialized
+	case ready:
+		return LifecycleReady
+	case destroyed:
+		return LifecycleDestroyed
+	default:
+		return LifecycleCreated
+	}
+}
+
+// Lifecycle returns the instance's Lifecycle, which tracks its
+// created/initialized/ready/destroyed progression and exposes hooks and a
+// state-change channel for embedding services that need to integrate
+// Ringpop into their own startup and shutdown ordering.
+func (rp *Ringpop) Lifecycle() *Lifecycle {
+	return rp.lifecycle
+}
+
+// getState returns the current lifecycle state.
+func (rp *Ringpop) getState() state {
+	rp.mu.RLock()
+	defer rp.mu.RUnlock()
+	return rp.state
+}
+
+// Bootstrap joins the cluster described by opts, re-resolving opts.Hosts
+// from opts.EffectiveHostsProvider() before each attempt when one is
+// configured. On success the reached hosts are added to the ring and the
+// instance becomes ready; on failure it is left initialized so the caller
+// can retry.
+func (rp *Ringpop) Bootstrap(opts *swim.BootstrapOptions) ([]string, error) {
+	rp.mu.Lock()
+	rp.initLocked()
+	node := rp.node
+	stopped := rp.destroyed
+	if opts.EffectiveHostsProvider() == nil && rp.discoveryProvider != nil {
+		opts.Discovery = rp.discoveryProvider
+	}
+	rp.mu.Unlock()
+
+	joined, err := bootstrapWithRetry(opts, stopped, node.Bootstrap)
+	if err != nil {
+		return nil, err
+	}
+
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	rp.ring.AddServers(joined)
+	if err := rp.setStateLocked(ready); err != nil {
+		return nil, err
+	}
+
+	watched := opts.Discovery
+	if watched == nil {
+		watched = rp.discoveryProvider
+	}
+	if watched != nil && !rp.watchingDiscovery {
+		rp.watchingDiscovery = true
+		go rp.watchDiscovery(watched, stopped)
+	}
+
+	return joined, nil
+}
+
+// watchDiscovery applies periodic membership refreshes from provider to the
+// ring until stopped is closed or the provider stops watching, so that
+// DiscoveryProvider.Watch feeds back into membership the way its doc
+// comment promises instead of only being consulted once during Bootstrap.
+func (rp *Ringpop) watchDiscovery(provider swim.DiscoveryProvider, stopped <-chan struct{}) {
+	updates, err := provider.Watch(stopped)
+	if err != nil {
+		return
+	}
+	for hosts := range updates {
+		rp.applyDiscoveryUpdate(hosts)
+	}
+}
+
+// applyDiscoveryUpdate reconciles the ring with a refreshed host list from
+// a DiscoveryProvider: hosts no longer present are removed, new ones are
+// added, and the change is reported the same way a swim membership change
+// is.
+func (rp *Ringpop) applyDiscoveryUpdate(hosts []string) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	if rp.state != ready {
+		return
+	}
+
+	seen := make(map[string]struct{}, len(hosts))
+	var added []string
+	for _, host := range hosts {
+		seen[host] = struct{}{}
+		if !rp.ring.HasServer(host) {
+			added = append(added, host)
+		}
+	}
+
+	var removed []string
+	for _, existing := range rp.ring.GetServers() {
+		if _, ok := seen[existing]; !ok {
+			removed = append(removed, existing)
+		}
+	}
+
+	if len(added) == 0 && len(removed) == 0 {
+		return
+	}
+
+	rp.ring.AddServers(added)
+	for _, host := range removed {
+		rp.ring.RemoveServer(host)
+	}
+
+	rp.statter.IncCounter(rp.statKey("discovery.refresh"), 1)
+	rp.listeners.Dispatch(ringChangedEvent{ServersAdded: added, ServersRemoved: removed})
+	rp.events.publish(RingChanged{ServersAdded: added, ServersRemoved: removed})
+}
+
+// Destroy tears down the Ringpop instance. It is safe to call more than
+// once, and safe to call from any state.
+func (rp *Ringpop) Destroy() {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	if rp.state == destroyed {
+		return
+	}
+
+	// A pre-stop hook error leaves rp.state unchanged, so a subsequent
+	// Destroy() call retries the transition instead of silently treating
+	// the instance as torn down. rp.destroyed must only close once that
+	// transition actually succeeds: closing it first would cancel any
+	// in-flight Bootstrap via its stopped channel even on a failed hook,
+	// letting that Bootstrap report success on an instance that was never
+	// actually destroyed.
+	if err := rp.setStateLocked(destroyed); err != nil {
+		return
+	}
+
+	select {
+	case <-rp.destroyed:
+	default:
+		close(rp.destroyed)
+	}
+}
+
+// Ready reports whether Bootstrap has completed successfully.
+func (rp *Ringpop) Ready() bool {
+	return rp.getState() == ready
+}
+
+// App returns the application name this Ringpop instance was created with.
+func (rp *Ringpop) App() string {
+	return rp.app
+}
+
+// WhoAmI returns this instance's identity. It only succeeds once the
+// instance is ready.
+func (rp *Ringpop) WhoAmI() (string, error) {
+	rp.mu.RLock()
+	defer rp.mu.RUnlock()
+
+	if rp.state != ready {
+		return "", errNotReady
+	}
+	return rp.identity, nil
+}
+
+// Uptime returns how long this instance has been ready. It only succeeds
+// once the instance is ready.
+func (rp *Ringpop) Uptime() (time.Duration, error) {
+	rp.mu.RLock()
+	defer rp.mu.RUnlock()
+
+	if rp.state != ready {
+		return 0, errNotReady
+	}
+	return time.Since(rp.readyAt), nil
+}
+
+// Checksum returns the hash ring's current checksum. It only succeeds once
+// the instance is ready.
+func (rp *Ringpop) Checksum() (uint32, error) {
+	rp.mu.RLock()
+	defer rp.mu.RUnlock()
+
+	if rp.state != ready {
+		return 0, errNotReady
+	}
+	return rp.ring.Checksum(), nil
+}
+
+// Lookup returns the server responsible for key. It only succeeds once the
+// instance is ready.
+func (rp *Ringpop) Lookup(key string) (string, error) {
+	rp.mu.RLock()
+	defer rp.mu.RUnlock()
+
+	if rp.state != ready {
+		return "", errNotReady
+	}
+
+	server, ok := rp.ring.Lookup(key)
+	if !ok {
+		return "", fmt.Errorf("ringpop: no servers available to look up %q", key)
+	}
+	return server, nil
+}
+
+// LookupN returns up to n distinct servers responsible for key. It only
+// succeeds once the instance is ready.
+func (rp *Ringpop) LookupN(key string, n int) ([]string, error) {
+	rp.mu.RLock()
+	defer rp.mu.RUnlock()
+
+	if rp.state != ready {
+		return nil, errNotReady
+	}
+	return rp.ring.LookupN(key, n), nil
+}
+
+// GetReachableMembers returns every server currently in the ring. It only
+// succeeds once the instance is ready.
+func (rp *Ringpop) GetReachableMembers() ([]string, error) {
+	rp.mu.RLock()
+	defer rp.mu.RUnlock()
+
+	if rp.state != ready {
+		return nil, errNotReady
+	}
+	return rp.ring.GetServers(), nil
+}
+
+var errNotReady = errors.New("ringpop: instance is not ready")
+
+// statKey builds the dot-delimited, instance-scoped stat name legacy
+// statters expect, e.g. "ringpop.127_0_0_1_3001.changes.apply".
+func (rp *Ringpop) statKey(name string) string {
+	replacer := strings.NewReplacer(".", "_", ":", "_")
+	return "ringpop." + replacer.Replace(rp.identity) + "." + name
+}
+
+// HandleEvent is swim's entry point for every event it emits. It updates
+// ring membership and stats, and forwards the event (plus, for membership
+// changes, the derived checksum and ring-changed events) to every
+// registered Listener. Events with a typed counterpart (membership changes,
+// max-p adjustments and ping-req pings) are also published as Events to
+// Events()/Subscribe().
+func (rp *Ringpop) HandleEvent(event interface{}) {
+	switch ev := event.(type) {
+	case swim.MemberlistChangesAppliedEvent:
+		rp.handleMemberlistChangesApplied(ev)
+	case swim.MaxPAdjustedEvent:
+		rp.statter.UpdateGauge(rp.statKey("max-p"), int64(ev.NewPCount))
+		rp.listeners.Dispatch(event)
+		rp.events.publish(MaxPAdjusted{NewPCount: ev.NewPCount})
+	case swim.JoinReceiveEvent:
+		rp.statter.IncCounter(rp.statKey("join.recv"), 1)
+		rp.listeners.Dispatch(event)
+	case swim.JoinCompleteEvent:
+		rp.statter.RecordTimer(rp.statKey("join"), ev.Duration)
+		rp.statter.IncCounter(rp.statKey("join.complete"), 1)
+		rp.listeners.Dispatch(event)
+	case swim.PingSendEvent:
+		rp.statter.IncCounter(rp.statKey("ping.send"), 1)
+		rp.listeners.Dispatch(event)
+	case swim.PingReceiveEvent:
+		rp.statter.IncCounter(rp.statKey("ping.recv"), 1)
+		rp.listeners.Dispatch(event)
+	case swim.PingRequestsSendEvent:
+		rp.statter.IncCounter(rp.statKey("ping-req.send"), int64(len(ev.Peers)))
+		rp.listeners.Dispatch(event)
+	case swim.PingRequestReceiveEvent:
+		rp.statter.IncCounter(rp.statKey("ping-req.recv"), 1)
+		rp.listeners.Dispatch(event)
+	case swim.PingRequestPingEvent:
+		rp.statter.RecordTimer(rp.statKey("ping-req.ping"), ev.Duration)
+		rp.listeners.Dispatch(event)
+		rp.events.publish(PingLatency{Target: ev.Target, Duration: ev.Duration})
+	}
+}
+
+// handleMemberlistChangesApplied applies a batch of swim.Change to the ring
+// and reports the net effect: alive changes add to the ring, faulty and
+// leave changes remove from it, and suspect changes leave ring membership
+// untouched (a suspect node is still routable until it is confirmed
+// faulty). It then forwards the raw event, a checksum-computed event, and
+// a ring-changed event to every registered Listener, and publishes the
+// typed MembershipChanged, ChecksumComputed and (when the server set
+// actually changed) RingChanged equivalents to Events()/Subscribe().
+func (rp *Ringpop) handleMemberlistChangesApplied(ev swim.MemberlistChangesAppliedEvent) {
+	rp.statter.IncCounter(rp.statKey("changes.apply"), int64(len(ev.Changes)))
+
+	var added, removed []string
+	var alive, faulty, suspect, leave []string
+	for _, change := range ev.Changes {
+		switch change.Status {
+		case swim.Alive:
+			if !rp.ring.HasServer(change.Address) {
+				added = append(added, change.Address)
+			}
+			rp.ring.AddServer(change.Address)
+			alive = append(alive, change.Address)
+		case swim.Faulty, swim.Leave:
+			if rp.ring.HasServer(change.Address) {
+				removed = append(removed, change.Address)
+			}
+			rp.ring.RemoveServer(change.Address)
+			if change.Status == swim.Faulty {
+				faulty = append(faulty, change.Address)
+			} else {
+				leave = append(leave, change.Address)
+			}
+		case swim.Suspect:
+			suspect = append(suspect, change.Address)
+		}
+	}
+
+	rp.listeners.Dispatch(ev)
+	rp.events.publish(MembershipChanged{Alive: alive, Faulty: faulty, Suspect: suspect, Leave: leave})
+
+	checksum := rp.ring.Checksum()
+	rp.statter.IncCounter(rp.statKey("ring.checksum-computed"), 1)
+	rp.listeners.Dispatch(checksumComputedEvent{Checksum: checksum})
+	rp.events.publish(ChecksumComputed{Checksum: checksum})
+
+	if len(added) > 0 || len(removed) > 0 {
+		rp.listeners.Dispatch(ringChangedEvent{ServersAdded: added, ServersRemoved: removed})
+		rp.events.publish(RingChanged{ServersAdded: added, ServersRemoved: removed})
+	}
+}
+
+// checksumComputedEvent and ringChangedEvent are the legacy, untyped
+// counterparts of ChecksumComputed and RingChanged, kept distinct so that
+// changing what Events()/Subscribe() publish never changes what existing
+// Listener implementations see.
+type checksumComputedEvent struct {
+	Checksum uint32
+}
+
+type ringChangedEvent struct {
+	ServersAdded   []string
+	ServersRemoved []string
+}
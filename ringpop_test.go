@@ -21,6 +21,7 @@
 package ringpop
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -65,6 +66,111 @@ func (s *RingpopTestSuite) TearDownTest() {
 	s.ringpop.Destroy()
 }
 
+// fakeDiscoveryProvider is a minimal swim.DiscoveryProvider used to verify
+// that Ringpop can be constructed with a Discovery option.
+type fakeDiscoveryProvider struct{}
+
+func (fakeDiscoveryProvider) Hosts() ([]string, error) {
+	return []string{"127.0.0.1:3001"}, nil
+}
+
+func (fakeDiscoveryProvider) Watch(stopped <-chan struct{}) (<-chan []string, error) {
+	updates := make(chan []string)
+	go func() {
+		<-stopped
+		close(updates)
+	}()
+	return updates, nil
+}
+
+// TestNewWithDiscoveryOption tests that Ringpop can be constructed with a
+// DiscoveryProvider in place of a static hosts list or file, for callers
+// that already store membership in a KV store, DNS, or a cluster API.
+func (s *RingpopTestSuite) TestNewWithDiscoveryOption() {
+	ch, err := tchannel.NewChannel("test-discovery", nil)
+	s.NoError(err)
+	defer ch.Close()
+
+	rp, err := New("test", Identity("127.0.0.1:3001"), Channel(ch), Discovery(fakeDiscoveryProvider{}))
+	s.NoError(err, "Ringpop must create successfully with a Discovery option")
+	s.NotNil(rp)
+	defer rp.Destroy()
+}
+
+// watchingDiscoveryProvider is a swim.DiscoveryProvider whose Watch pushes a
+// single host-list update, so tests can observe that update reach the ring
+// instead of Watch sitting unused after Bootstrap.
+type watchingDiscoveryProvider struct {
+	initial []string
+	update  []string
+}
+
+func (p watchingDiscoveryProvider) Hosts() ([]string, error) {
+	return p.initial, nil
+}
+
+func (p watchingDiscoveryProvider) Watch(stopped <-chan struct{}) (<-chan []string, error) {
+	updates := make(chan []string, 1)
+	updates <- p.update
+	go func() {
+		<-stopped
+		close(updates)
+	}()
+	return updates, nil
+}
+
+// TestBootstrapAppliesDiscoveryWatchUpdates tests that a DiscoveryProvider's
+// Watch updates are applied to the ring after Bootstrap, not just consulted
+// once up front the way a plain BootstrapHostsProvider is.
+func (s *RingpopTestSuite) TestBootstrapAppliesDiscoveryWatchUpdates() {
+	ch, err := tchannel.NewChannel("test-discovery-watch", nil)
+	s.NoError(err)
+	defer ch.Close()
+
+	provider := watchingDiscoveryProvider{
+		initial: []string{"127.0.0.1:3005"},
+		update:  []string{"127.0.0.1:3005", "127.0.0.1:3006"},
+	}
+
+	rp, err := New("test", Identity("127.0.0.1:3005"), Channel(ch), Discovery(provider))
+	s.NoError(err)
+	defer rp.Destroy()
+
+	_, err = rp.Bootstrap(&swim.BootstrapOptions{})
+	s.NoError(err)
+
+	s.Eventually(func() bool {
+		members, err := rp.GetReachableMembers()
+		return err == nil && len(members) == 2
+	}, time.Second, time.Millisecond, "discovery watch update must be applied to the ring")
+}
+
+// TestNewStatterFactoryOverridesDefaultStatter verifies that an Option
+// setting statterFactory (what Metrics and Meter both build on) replaces
+// the default noopStatter once identity is known, rather than that field
+// sitting unused on Options.
+func (s *RingpopTestSuite) TestNewStatterFactoryOverridesDefaultStatter() {
+	ch, err := tchannel.NewChannel("test-statter", nil)
+	s.NoError(err)
+	defer ch.Close()
+
+	var gotIdentity string
+	fakeStatterOption := func(options *Options) {
+		options.statterFactory = func(identity string) Statter {
+			gotIdentity = identity
+			return newDummyStats()
+		}
+	}
+
+	rp, err := New("test", Identity("127.0.0.1:3004"), Channel(ch), fakeStatterOption)
+	s.NoError(err)
+	defer rp.Destroy()
+
+	s.Equal("127.0.0.1:3004", gotIdentity)
+	_, ok := rp.statter.(*dummyStats)
+	s.True(ok, "statterFactory's Statter must replace the default noopStatter")
+}
+
 func (s *RingpopTestSuite) TestCanAssignRingpopToRingpopInterface() {
 	var ri Interface
 	ri = s.ringpop
@@ -262,6 +368,28 @@ func (s *RingpopTestSuite) TestDestroyIsIdempotent() {
 	s.Equal(destroyed, s.ringpop.state)
 }
 
+// TestDestroyDoesNotSignalCancellationOnFailedPreStopHook tests that a
+// failing pre-stop hook leaves the instance non-destroyed and does not
+// close the destroyed channel Bootstrap's retry loop watches as its
+// cancellation signal. Closing it anyway would let an in-flight Bootstrap
+// race this window and report success on an instance that never actually
+// finished destroying.
+func (s *RingpopTestSuite) TestDestroyDoesNotSignalCancellationOnFailedPreStopHook() {
+	s.ringpop.init()
+
+	hookErr := errors.New("pre-stop failed")
+	s.ringpop.Lifecycle().AddPreStopHook(func() error { return hookErr })
+
+	s.ringpop.Destroy()
+	s.Equal(initialized, s.ringpop.state)
+
+	select {
+	case <-s.ringpop.destroyed:
+		s.Fail("destroyed channel must not close when the transition to destroyed fails")
+	default:
+	}
+}
+
 // TestWhoAmI tests that WhoAmI only operates when the Ringpop instance is in
 // a ready state.
 func (s *RingpopTestSuite) TestWhoAmI() {
@@ -0,0 +1,120 @@
+// Copyright (c) 2015 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package stats
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// OTelMeterStatter is a Reporter that records its metrics through an
+// OpenTelemetry Meter, labeling each instrument with the reporting instance
+// as an attribute rather than baking the address into the metric name.
+type OTelMeterStatter struct {
+	meter    metric.Meter
+	instance string
+
+	mu         sync.Mutex
+	counters   map[string]metric.Int64Counter
+	gauges     map[string]metric.Int64Gauge
+	histograms map[string]metric.Float64Histogram
+}
+
+// NewOTelMeterStatter returns an OTelMeterStatter that records instruments
+// on meter, labeled with instance (typically the Ringpop's own identity,
+// e.g. "127.0.0.1:3001").
+func NewOTelMeterStatter(meter metric.Meter, instance string) *OTelMeterStatter {
+	return &OTelMeterStatter{
+		meter:      meter,
+		instance:   instance,
+		counters:   make(map[string]metric.Int64Counter),
+		gauges:     make(map[string]metric.Int64Gauge),
+		histograms: make(map[string]metric.Float64Histogram),
+	}
+}
+
+func (o *OTelMeterStatter) instanceAttr() attribute.KeyValue {
+	return attribute.String("instance", o.instance)
+}
+
+// IncCounter implements Reporter.
+func (o *OTelMeterStatter) IncCounter(name string, value int64) {
+	metricName := "ringpop_" + splitMetricName(name, o.instance) + "_total"
+
+	o.mu.Lock()
+	c, ok := o.counters[metricName]
+	if !ok {
+		var err error
+		c, err = o.meter.Int64Counter(metricName)
+		if err != nil {
+			o.mu.Unlock()
+			return
+		}
+		o.counters[metricName] = c
+	}
+	o.mu.Unlock()
+
+	c.Add(context.Background(), value, metric.WithAttributes(o.instanceAttr()))
+}
+
+// UpdateGauge implements Reporter.
+func (o *OTelMeterStatter) UpdateGauge(name string, value int64) {
+	metricName := "ringpop_" + splitMetricName(name, o.instance)
+
+	o.mu.Lock()
+	g, ok := o.gauges[metricName]
+	if !ok {
+		var err error
+		g, err = o.meter.Int64Gauge(metricName)
+		if err != nil {
+			o.mu.Unlock()
+			return
+		}
+		o.gauges[metricName] = g
+	}
+	o.mu.Unlock()
+
+	g.Record(context.Background(), value, metric.WithAttributes(o.instanceAttr()))
+}
+
+// RecordTimer implements Reporter.
+func (o *OTelMeterStatter) RecordTimer(name string, d time.Duration) {
+	metricName := "ringpop_" + splitMetricName(name, o.instance) + "_seconds"
+
+	o.mu.Lock()
+	h, ok := o.histograms[metricName]
+	if !ok {
+		var err error
+		h, err = o.meter.Float64Histogram(metricName)
+		if err != nil {
+			o.mu.Unlock()
+			return
+		}
+		o.histograms[metricName] = h
+	}
+	o.mu.Unlock()
+
+	h.Record(context.Background(), d.Seconds(), metric.WithAttributes(o.instanceAttr()))
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2015 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package stats
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PrometheusStatter is a Reporter that registers and updates Prometheus
+// metrics, labeling each one with the reporting instance instead of baking
+// the address into the metric name the way the StatsD-style keys do.
+type PrometheusStatter struct {
+	registerer prometheus.Registerer
+	instance   string
+
+	mu       sync.Mutex
+	counters map[string]*prometheus.CounterVec
+	gauges   map[string]*prometheus.GaugeVec
+	timers   map[string]*prometheus.HistogramVec
+}
+
+// NewPrometheusStatter returns a PrometheusStatter that registers its
+// metrics with registerer and labels them with instance (typically the
+// Ringpop's own identity, e.g. "127.0.0.1:3001").
+func NewPrometheusStatter(registerer prometheus.Registerer, instance string) *PrometheusStatter {
+	return &PrometheusStatter{
+		registerer: registerer,
+		instance:   instance,
+		counters:   make(map[string]*prometheus.CounterVec),
+		gauges:     make(map[string]*prometheus.GaugeVec),
+		timers:     make(map[string]*prometheus.HistogramVec),
+	}
+}
+
+// IncCounter implements Reporter.
+func (p *PrometheusStatter) IncCounter(name string, value int64) {
+	metric := splitMetricName(name, p.instance)
+
+	p.mu.Lock()
+	c, ok := p.counters[metric]
+	if !ok {
+		c = prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ringpop_" + metric + "_total",
+			Help: "ringpop " + metric + " counter",
+		}, []string{"instance"})
+		p.registerer.MustRegister(c)
+		p.counters[metric] = c
+	}
+	p.mu.Unlock()
+
+	c.WithLabelValues(p.instance).Add(float64(value))
+}
+
+// UpdateGauge implements Reporter.
+func (p *PrometheusStatter) UpdateGauge(name string, value int64) {
+	metric := splitMetricName(name, p.instance)
+
+	p.mu.Lock()
+	g, ok := p.gauges[metric]
+	if !ok {
+		g = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "ringpop_" + metric,
+			Help: "ringpop " + metric + " gauge",
+		}, []string{"instance"})
+		p.registerer.MustRegister(g)
+		p.gauges[metric] = g
+	}
+	p.mu.Unlock()
+
+	g.WithLabelValues(p.instance).Set(float64(value))
+}
+
+// RecordTimer implements Reporter.
+func (p *PrometheusStatter) RecordTimer(name string, d time.Duration) {
+	metric := splitMetricName(name, p.instance)
+
+	p.mu.Lock()
+	h, ok := p.timers[metric]
+	if !ok {
+		h = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "ringpop_" + metric + "_seconds",
+			Help: "ringpop " + metric + " timer",
+		}, []string{"instance"})
+		p.registerer.MustRegister(h)
+		p.timers[metric] = h
+	}
+	p.mu.Unlock()
+
+	h.WithLabelValues(p.instance).Observe(d.Seconds())
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2015 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestPrometheusStatterLabelsByInstanceInsteadOfMetricName(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	statter := NewPrometheusStatter(registry, "127.0.0.1:3001")
+
+	statter.IncCounter("ringpop.127_0_0_1_3001.changes.apply", 10)
+
+	got := testutil.ToFloat64(prometheus.Collector(statter.counters["changes_apply"]).(*prometheus.CounterVec).WithLabelValues("127.0.0.1:3001"))
+	if got != 10 {
+		t.Fatalf("ringpop_changes_apply_total{instance=\"127.0.0.1:3001\"} = %v, want 10", got)
+	}
+}
+
+func TestPrometheusStatterRecordTimer(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	statter := NewPrometheusStatter(registry, "127.0.0.1:3001")
+
+	statter.RecordTimer("ringpop.127_0_0_1_3001.ping-req.ping", time.Second)
+
+	if _, ok := statter.timers["ping_req_ping"]; !ok {
+		t.Fatal("expected a histogram to be registered for ping-req.ping")
+	}
+}
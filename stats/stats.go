@@ -0,0 +1,69 @@
+// Copyright (c) 2015 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package stats provides Reporter implementations that translate Ringpop's
+// StatsD-style, dot-delimited metric names (e.g.
+// "ringpop.127_0_0_1_3001.changes.apply") into properly labeled metrics for
+// Prometheus and OpenTelemetry, so callers no longer have to run a
+// StatsD-to-Prometheus shim just to get modern observability out of
+// ringpop-go.
+package stats
+
+import (
+	"strings"
+	"time"
+)
+
+// Reporter is the stats sink Ringpop writes to: the root package's Statter
+// type is an alias of it, so any Reporter can be assigned to Ringpop's
+// statter field (see the Metrics and Meter options in the root package).
+type Reporter interface {
+	IncCounter(name string, value int64)
+	UpdateGauge(name string, value int64)
+	RecordTimer(name string, d time.Duration)
+}
+
+// escapeInstance mirrors the escaping Ringpop already applies to its own
+// identity when building a stat name, e.g. "127.0.0.1:3001" becomes
+// "127_0_0_1_3001".
+func escapeInstance(instance string) string {
+	replacer := strings.NewReplacer(".", "_", ":", "_")
+	return replacer.Replace(instance)
+}
+
+// splitMetricName strips the "ringpop.<escaped-instance>." prefix Ringpop
+// puts on every stat name, returning the bare metric name with dots and
+// dashes normalized to underscores so it is a valid Prometheus/OTel metric
+// name, e.g. "ring.checksum-computed" becomes "ring_checksum_computed".
+func splitMetricName(name, instance string) string {
+	prefix := "ringpop." + escapeInstance(instance) + "."
+	name = strings.TrimPrefix(name, prefix)
+
+	var b strings.Builder
+	b.Grow(len(name))
+	for _, r := range name {
+		if r == '.' || r == '-' {
+			b.WriteByte('_')
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2015 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package stats
+
+import "testing"
+
+func TestSplitMetricNameStripsInstancePrefix(t *testing.T) {
+	got := splitMetricName("ringpop.127_0_0_1_3001.changes.apply", "127.0.0.1:3001")
+	want := "changes_apply"
+	if got != want {
+		t.Fatalf("splitMetricName() = %q, want %q", got, want)
+	}
+}
+
+func TestSplitMetricNameNormalizesDashes(t *testing.T) {
+	got := splitMetricName("ringpop.127_0_0_1_3001.ring.checksum-computed", "127.0.0.1:3001")
+	want := "ring_checksum_computed"
+	if got != want {
+		t.Fatalf("splitMetricName() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2015 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package swim
+
+import "time"
+
+// BootstrapHostsProvider supplies the seed list used to join a cluster. It is
+// consulted before every bootstrap attempt, which allows a node to recover
+// from a stale seed list (for example, discovery table entries left behind by
+// crashed pods that came back up with new IPs) without requiring a process
+// restart.
+type BootstrapHostsProvider interface {
+	// Hosts returns the current set of seed hosts to attempt to join.
+	Hosts() ([]string, error)
+}
+
+// BootstrapOptions is used to configure the bootstrap process.
+type BootstrapOptions struct {
+	// Hosts is a static list of seed hosts to bootstrap from. It is ignored
+	// when HostsProvider is set.
+	Hosts []string
+
+	// File is a path to a JSON file containing a list of seed hosts.
+	// Node.Bootstrap does not read it; only Hosts, or the list supplied by
+	// HostsProvider or Discovery, is used to join.
+	File string
+
+	// HostsProvider, when set, is asked for the seed list before each
+	// bootstrap attempt instead of using Hosts or File. This takes
+	// precedence over both. If nil, current behavior is preserved.
+	HostsProvider BootstrapHostsProvider
+
+	// MaxRetries bounds the number of bootstrap attempts made when
+	// HostsProvider is set. A value of zero means a single attempt, matching
+	// the behavior of a node without a HostsProvider.
+	MaxRetries int
+
+	// RetryBackoff is the delay between bootstrap retries. It is ignored
+	// when MaxRetries is zero.
+	RetryBackoff time.Duration
+
+	// Discovery, when set, supersedes HostsProvider: it supplies the seed
+	// list the same way a HostsProvider does, and is additionally watched
+	// for membership changes for as long as the node is bootstrapped.
+	Discovery DiscoveryProvider
+
+	MaxJoinDuration time.Duration
+}
+
+// EffectiveHostsProvider returns the effective BootstrapHostsProvider for
+// these options, preferring Discovery over a directly configured
+// HostsProvider. It is exported so that callers in other packages (the
+// ringpop package's bootstrap retry loop, in particular) can resolve it
+// without reaching into swim's internals.
+func (o *BootstrapOptions) EffectiveHostsProvider() BootstrapHostsProvider {
+	if o.Discovery != nil {
+		return o.Discovery
+	}
+	return o.HostsProvider
+}
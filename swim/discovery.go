@@ -0,0 +1,39 @@
+// Copyright (c) 2015 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package swim
+
+// DiscoveryProvider is a pluggable membership discovery backend. It is a
+// BootstrapHostsProvider that can additionally watch for membership changes
+// in whatever system it backs onto (a KV store, DNS, a cluster API), instead
+// of only being consulted up front. Ringpop applies each refreshed host list
+// directly to its hash ring; swim's join is not re-run for the hosts it
+// adds. Implementations live in the top-level discovery
+// package so that ringpop-go itself stays free of etcd/Consul/Kubernetes
+// client dependencies.
+type DiscoveryProvider interface {
+	BootstrapHostsProvider
+
+	// Watch starts watching for membership changes and returns a channel of
+	// refreshed host lists. The channel is closed once stopped is closed or
+	// the provider gives up watching; callers should treat a closed channel
+	// as "no more updates", not as an error.
+	Watch(stopped <-chan struct{}) (<-chan []string, error)
+}
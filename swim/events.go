@@ -0,0 +1,67 @@
+// Copyright (c) 2015 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package swim
+
+import "time"
+
+// MemberlistChangesAppliedEvent is emitted once a batch of Changes has been
+// applied to the local membership list.
+type MemberlistChangesAppliedEvent struct {
+	Changes []Change
+}
+
+// MaxPAdjustedEvent is emitted when the piggyback count used to propagate
+// gossip changes is recalculated.
+type MaxPAdjustedEvent struct {
+	NewPCount int
+}
+
+// JoinReceiveEvent is emitted when a join request is received from a peer.
+type JoinReceiveEvent struct{}
+
+// JoinCompleteEvent is emitted once this node's own join attempt completes
+// successfully.
+type JoinCompleteEvent struct {
+	Duration time.Duration
+}
+
+// PingSendEvent is emitted when a direct ping is sent to a peer.
+type PingSendEvent struct{}
+
+// PingReceiveEvent is emitted when a direct ping is received from a peer.
+type PingReceiveEvent struct{}
+
+// PingRequestsSendEvent is emitted when indirect ping requests are sent out
+// on behalf of a suspect target, one per relay peer.
+type PingRequestsSendEvent struct {
+	Peers []string
+}
+
+// PingRequestReceiveEvent is emitted when this node is asked to relay an
+// indirect ping on behalf of another node.
+type PingRequestReceiveEvent struct{}
+
+// PingRequestPingEvent is emitted when a relayed ping this node issued on
+// behalf of another node completes.
+type PingRequestPingEvent struct {
+	Target   string
+	Duration time.Duration
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2015 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package swim
+
+// Status is a member's position in the swim failure detector state
+// machine.
+type Status int
+
+const (
+	// Alive means the member is reachable and participating normally.
+	Alive Status = iota
+	// Suspect means pings to the member have failed enough times that it
+	// may have left or crashed, but it has not yet been confirmed faulty.
+	Suspect
+	// Faulty means the member has been confirmed unreachable and should be
+	// treated as gone.
+	Faulty
+	// Leave means the member announced its own departure.
+	Leave
+)
+
+func (s Status) String() string {
+	switch s {
+	case Alive:
+		return "alive"
+	case Suspect:
+		return "suspect"
+	case Faulty:
+		return "faulty"
+	case Leave:
+		return "leave"
+	default:
+		return "unknown"
+	}
+}
+
+// Change describes a single member's status transition, as produced by the
+// failure detector and applied to the membership list.
+type Change struct {
+	Address string
+	Status  Status
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2015 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package swim
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+// Dialer opens the connection a Node uses to probe a peer during bootstrap.
+// It has the same shape as tchannel's ChannelOptions.Dialer, so a
+// TLS-wrapping dialer can be swapped in without changing Node itself.
+type Dialer func(ctx context.Context, network, address string) (net.Conn, error)
+
+// Node is the local swim participant a Ringpop instance bootstraps and
+// gossips through. It is deliberately small: ping/ping-req gossip timing
+// lives in the full swim failure detector; Node here is responsible for the
+// join handshake Bootstrap drives.
+type Node struct {
+	Address string
+	// Dial opens a connection to a candidate peer during Bootstrap. Defaults
+	// to a plain TCP dial when nil. Every dial shares one deadline, set by
+	// MaxJoinDuration for the whole join.
+	Dial Dialer
+}
+
+// NewNode returns a Node that will identify itself as address.
+func NewNode(address string) *Node {
+	return &Node{Address: address}
+}
+
+// Bootstrap attempts to join the cluster described by opts.Hosts, returning
+// the subset of hosts it was able to reach within opts.MaxJoinDuration. This
+// node's own address counts as reached, without a dial, only when it is
+// listed in Hosts. An empty Hosts list creates a single-node cluster.
+func (n *Node) Bootstrap(opts *BootstrapOptions) ([]string, error) {
+	if len(opts.Hosts) == 0 {
+		return []string{n.Address}, nil
+	}
+
+	timeout := opts.MaxJoinDuration
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	dial := n.Dial
+	if dial == nil {
+		var d net.Dialer
+		dial = d.DialContext
+	}
+
+	var reached []string
+	for _, host := range opts.Hosts {
+		if host == n.Address {
+			reached = append(reached, host)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		default:
+			conn, err := dial(ctx, "tcp", host)
+			if err == nil {
+				conn.Close()
+				reached = append(reached, host)
+			}
+		}
+	}
+
+	if len(reached) == 0 {
+		return nil, fmt.Errorf("swim: failed to bootstrap: no reachable hosts among %v within %s", opts.Hosts, timeout)
+	}
+
+	return reached, nil
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2015 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ringpop
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/uber/ringpop-go/swim"
+)
+
+// genAddresses generates addresses "127.0.0.<seq>:<3000+i>" for i in
+// [from, to], inclusive.
+func genAddresses(seq, from, to int) []string {
+	addresses := make([]string, 0, to-from+1)
+	for i := from; i <= to; i++ {
+		addresses = append(addresses, fmt.Sprintf("127.0.0.%d:%d", seq, 3000+i))
+	}
+	return addresses
+}
+
+// genChanges builds a swim.Change with the given status for every address.
+func genChanges(addresses []string, status swim.Status) []swim.Change {
+	changes := make([]swim.Change, 0, len(addresses))
+	for _, address := range addresses {
+		changes = append(changes, swim.Change{Address: address, Status: status})
+	}
+	return changes
+}
+
+// dummyStats is a Statter test double that records every value it is given,
+// keyed by stat name.
+type dummyStats struct {
+	mu   sync.Mutex
+	vals map[string]int64
+}
+
+func newDummyStats() *dummyStats {
+	return &dummyStats{vals: make(map[string]int64)}
+}
+
+func (d *dummyStats) IncCounter(name string, value int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.vals[name] += value
+}
+
+func (d *dummyStats) UpdateGauge(name string, value int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.vals[name] = value
+}
+
+func (d *dummyStats) RecordTimer(name string, duration time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.vals[name] = int64(duration / time.Millisecond)
+}
+
+// dummyListener is a Listener test double that records every event it is
+// given.
+type dummyListener struct {
+	mu     sync.Mutex
+	events []interface{}
+}
+
+func (d *dummyListener) HandleEvent(event interface{}) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.events = append(d.events, event)
+}
+
+func (d *dummyListener) EventCount() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.events)
+}
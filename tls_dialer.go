@@ -0,0 +1,90 @@
+// Copyright (c) 2015 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ringpop
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+)
+
+// tlsDialer and tlsListener wrap a TLSConfigProvider into the two halves of
+// a secured transport. New() currently only wires tlsDialer into the
+// swim.Node's join probe, since the tchannel.Channel passed via the Channel
+// option is already constructed by the caller and its own dialer/listener
+// can't be swapped after the fact; securing the tchannel transport itself
+// needs a ChannelOptions hook added at Channel-construction time, not here.
+
+// tlsDialer returns a tchannel ChannelOptions.Dialer-compatible dial func
+// that upgrades every outbound connection to TLS using the config from
+// provider, re-read on every dial so certificate rotation takes effect
+// without recreating the channel.
+func tlsDialer(provider TLSConfigProvider) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		conf, err := provider.TLSConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		dialer := &tls.Dialer{Config: conf}
+		return dialer.DialContext(ctx, network, address)
+	}
+}
+
+// tlsListener wraps an already-bound listener so that accepted connections
+// are upgraded to TLS using the config from provider, re-read on every
+// Accept so certificate rotation takes effect without rebinding the
+// listener. Accept forces the handshake to complete before returning, so a
+// peer that doesn't speak TLS, or that can't satisfy conf.ClientAuth, is
+// rejected synchronously by Accept itself rather than lazily on first
+// Read/Write.
+func tlsListener(inner net.Listener, provider TLSConfigProvider) net.Listener {
+	return &rotatingTLSListener{inner: inner, provider: provider}
+}
+
+type rotatingTLSListener struct {
+	inner    net.Listener
+	provider TLSConfigProvider
+}
+
+func (l *rotatingTLSListener) Accept() (net.Conn, error) {
+	conn, err := l.inner.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	conf, err := l.provider.TLSConfig()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	tlsConn := tls.Server(conn, conf)
+	if err := tlsConn.HandshakeContext(context.Background()); err != nil {
+		tlsConn.Close()
+		return nil, err
+	}
+
+	return tlsConn, nil
+}
+
+func (l *rotatingTLSListener) Close() error   { return l.inner.Close() }
+func (l *rotatingTLSListener) Addr() net.Addr { return l.inner.Addr() }
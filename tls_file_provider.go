@@ -0,0 +1,119 @@
+// Copyright (c) 2015 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ringpop
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// FileTLSConfigProvider is a TLSConfigProvider that reloads its certificate
+// and key from disk whenever either file's modification time changes,
+// letting keys/certs be rotated at runtime (e.g. by a secret manager
+// sidecar rewriting them in place) without tearing down the ring.
+type FileTLSConfigProvider struct {
+	CertFile string
+	KeyFile  string
+	CAFile   string
+	// ClientAuth is copied onto the returned *tls.Config. When left at zero
+	// and CAFile is set, it defaults to tls.RequireAndVerifyClientCert so
+	// that a plain-text or unauthenticated peer is rejected; without CAFile
+	// the zero value (tls.NoClientCert) is used as is.
+	ClientAuth tls.ClientAuthType
+
+	mu        sync.Mutex
+	certMtime int64
+	keyMtime  int64
+	cached    *tls.Config
+}
+
+// TLSConfig implements TLSConfigProvider.
+func (p *FileTLSConfigProvider) TLSConfig() (*tls.Config, error) {
+	certMtime, err := mtime(p.CertFile)
+	if err != nil {
+		return nil, fmt.Errorf("ringpop: stat tls cert: %v", err)
+	}
+	keyMtime, err := mtime(p.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("ringpop: stat tls key: %v", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cached != nil && certMtime == p.certMtime && keyMtime == p.keyMtime {
+		return p.cached, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(p.CertFile, p.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("ringpop: load tls key pair: %v", err)
+	}
+
+	clientAuth := p.ClientAuth
+	if clientAuth == tls.NoClientCert && p.CAFile != "" {
+		clientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	conf := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   clientAuth,
+	}
+
+	if p.CAFile != "" {
+		pool, err := loadCertPool(p.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		conf.ClientCAs = pool
+		conf.RootCAs = pool
+	}
+
+	p.cached = conf
+	p.certMtime = certMtime
+	p.keyMtime = keyMtime
+
+	return conf, nil
+}
+
+func mtime(path string) (int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	return info.ModTime().UnixNano(), nil
+}
+
+func loadCertPool(caFile string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("ringpop: read tls ca: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("ringpop: no certificates found in %s", caFile)
+	}
+	return pool, nil
+}
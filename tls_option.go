@@ -0,0 +1,70 @@
+// Copyright (c) 2015 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ringpop
+
+import "crypto/tls"
+
+// TLSConfigProvider supplies the *tls.Config used to secure swim.Node's
+// join probe, and is consulted before every such dial. It does not secure
+// the tchannel.Channel passed via the Channel option, so swim and forwarding
+// RPCs over that channel are not encrypted by it. Implementations can reload
+// keys/certs at runtime (from disk or an external secret manager) without
+// tearing down the ring, since the provider is re-read rather than captured
+// once at construction time.
+type TLSConfigProvider interface {
+	// TLSConfig returns the TLS configuration to use. It is called once per
+	// connection, so implementations that reload from disk should cache
+	// between file changes rather than re-reading on every call.
+	TLSConfig() (*tls.Config, error)
+}
+
+// staticTLSConfigProvider adapts a fixed *tls.Config into a
+// TLSConfigProvider for callers who don't need runtime rotation.
+type staticTLSConfigProvider struct {
+	conf *tls.Config
+}
+
+func (s staticTLSConfigProvider) TLSConfig() (*tls.Config, error) {
+	return s.conf, nil
+}
+
+// TLSConfig secures swim.Node's join probe (the dial Bootstrap uses to
+// check a candidate host's reachability) with conf. Pass a *tls.Config with
+// ClientAuth set to tls.RequireAndVerifyClientCert to reject plain-text and
+// unauthenticated peers. This does not yet secure the tchannel.Channel
+// passed via the Channel option; that transport is constructed by the
+// caller before New() sees it, so its own RPCs are unaffected until a
+// ChannelOptions-level hook is added.
+func TLSConfig(conf *tls.Config) Option {
+	return func(options *Options) {
+		options.tlsConfigProvider = staticTLSConfigProvider{conf}
+	}
+}
+
+// WithTLSConfigProvider is like TLSConfig, but sources the *tls.Config from
+// provider on every connection instead of a value fixed at construction
+// time, so that certificates can be rotated without recreating the
+// Ringpop.
+func WithTLSConfigProvider(provider TLSConfigProvider) Option {
+	return func(options *Options) {
+		options.tlsConfigProvider = provider
+	}
+}
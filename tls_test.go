@@ -0,0 +1,277 @@
+// Copyright (c) 2015 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package ringpop
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/ringpop-go/swim"
+	"github.com/uber/tchannel-go"
+)
+
+// selfSignedCert generates an in-memory self-signed certificate and key,
+// PEM-encoded, for use as both a leaf and its own CA in tests.
+func selfSignedCert(t *testing.T, commonName string) (certPEM, keyPEM []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		DNSNames:              []string{commonName},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	return certPEM, keyPEM
+}
+
+// TestFileTLSConfigProviderReloadsOnFileChange tests that rewriting the
+// cert/key on disk (as an external secret manager rotating them would)
+// is picked up on the next TLSConfig() call without recreating the
+// provider.
+func TestFileTLSConfigProviderReloadsOnFileChange(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+
+	cert1, key1 := selfSignedCert(t, "node-1")
+	require.NoError(t, os.WriteFile(certFile, cert1, 0600))
+	require.NoError(t, os.WriteFile(keyFile, key1, 0600))
+
+	provider := &FileTLSConfigProvider{CertFile: certFile, KeyFile: keyFile}
+
+	conf1, err := provider.TLSConfig()
+	require.NoError(t, err)
+	require.Len(t, conf1.Certificates, 1)
+
+	// Re-reading without a change returns the cached config.
+	conf1Again, err := provider.TLSConfig()
+	require.NoError(t, err)
+	assert.Same(t, conf1, conf1Again)
+
+	// Simulate rotation: new cert/key written in place with a later mtime.
+	time.Sleep(10 * time.Millisecond)
+	cert2, key2 := selfSignedCert(t, "node-1-rotated")
+	require.NoError(t, os.WriteFile(certFile, cert2, 0600))
+	require.NoError(t, os.WriteFile(keyFile, key2, 0600))
+
+	conf2, err := provider.TLSConfig()
+	require.NoError(t, err)
+	assert.NotSame(t, conf1, conf2)
+	assert.False(t, bytes.Equal(conf1.Certificates[0].Certificate[0], conf2.Certificates[0].Certificate[0]))
+}
+
+// TestTLSListenerRejectsPlainTextPeer tests that a peer which doesn't speak
+// TLS at all is rejected by a tlsListener configured to require client
+// certificates. It exercises tlsListener directly; New() does not wire a
+// listener into the tchannel.Channel.
+func TestTLSListenerRejectsPlainTextPeer(t *testing.T) {
+	certPEM, keyPEM := selfSignedCert(t, "server")
+	serverCert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(certPEM))
+
+	serverProvider := staticTLSConfigProvider{conf: &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+	}}
+
+	rawListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer rawListener.Close()
+
+	listener := tlsListener(rawListener, serverProvider)
+
+	acceptErrs := make(chan error, 1)
+	go func() {
+		_, err := listener.Accept()
+		acceptErrs <- err
+	}()
+
+	plainConn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	plainConn.Write([]byte("x"))
+	// A real TLS record header is 5 bytes; closing here short of that
+	// forces the in-progress handshake to fail on the read instead of
+	// blocking for more bytes that will never arrive.
+	plainConn.Close()
+
+	select {
+	case err := <-acceptErrs:
+		assert.Error(t, err, "a plain-text peer must be rejected by the TLS handshake")
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the plain-text peer to be rejected")
+	}
+}
+
+// TestTLSDialerAndListenerEstablishMTLSConnection tests that a client using
+// tlsDialer with a certificate signed by the server's trusted CA
+// successfully completes an mTLS handshake against a tlsListener.
+func TestTLSDialerAndListenerEstablishMTLSConnection(t *testing.T) {
+	certPEM, keyPEM := selfSignedCert(t, "node")
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(certPEM))
+
+	conf := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+		RootCAs:      pool,
+		ServerName:   "node",
+	}
+	provider := staticTLSConfigProvider{conf: conf}
+
+	rawListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer rawListener.Close()
+
+	listener := tlsListener(rawListener, provider)
+
+	serverDone := make(chan error, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			serverDone <- err
+			return
+		}
+		defer conn.Close()
+		buf := make([]byte, 5)
+		_, err = conn.Read(buf)
+		serverDone <- err
+	}()
+
+	dial := tlsDialer(provider)
+	conn, err := dial(context.Background(), "tcp", listener.Addr().String())
+	require.NoError(t, err, "mTLS handshake between two trusted peers must succeed")
+	defer conn.Close()
+	_, err = conn.Write([]byte("hello"))
+	require.NoError(t, err)
+
+	select {
+	case err := <-serverDone:
+		assert.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for server to read from the mTLS connection")
+	}
+}
+
+// TestRingpopBootstrapReachesPeerOverMTLS exercises WithTLSConfigProvider
+// through a real Ringpop.Bootstrap call against a second, mTLS-listening
+// peer, rather than calling tlsDialer/tlsListener directly. This is still
+// not the two-node swim gossip exchange the original request asked for:
+// ringpop-go's ping/ping-req/join RPCs aren't implemented anywhere in this
+// tree (see the tls_dialer.go and tls_option.go doc comments), so there is
+// no gossip traffic to run over mTLS yet. What this does verify is that
+// New()'s TLSConfigProvider wiring reaches the swim.Node Bootstrap actually
+// uses, end to end, instead of only the raw tlsDialer/tlsListener pair.
+func TestRingpopBootstrapReachesPeerOverMTLS(t *testing.T) {
+	certPEM, keyPEM := selfSignedCert(t, "node")
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(certPEM))
+
+	conf := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+		RootCAs:      pool,
+		ServerName:   "node",
+	}
+	provider := staticTLSConfigProvider{conf: conf}
+
+	// Stands in for the peer node: a bare mTLS listener, since there is no
+	// second Ringpop/swim RPC server to bootstrap against.
+	rawListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer rawListener.Close()
+	peerListener := tlsListener(rawListener, provider)
+
+	accepted := make(chan error, 1)
+	go func() {
+		conn, err := peerListener.Accept()
+		if err == nil {
+			conn.Close()
+		}
+		accepted <- err
+	}()
+
+	ch, err := tchannel.NewChannel("node-a", nil)
+	require.NoError(t, err)
+	defer ch.Close()
+
+	rp, err := New("test", Identity("127.0.0.1:3101"), Channel(ch), WithTLSConfigProvider(provider))
+	require.NoError(t, err)
+	defer rp.Destroy()
+
+	joined, err := rp.Bootstrap(&swim.BootstrapOptions{
+		Hosts: []string{"127.0.0.1:3101", peerListener.Addr().String()},
+	})
+	require.NoError(t, err, "bootstrap must reach the mTLS-listening peer")
+	assert.ElementsMatch(t, []string{"127.0.0.1:3101", peerListener.Addr().String()}, joined)
+
+	select {
+	case err := <-accepted:
+		assert.NoError(t, err, "peer must accept the mTLS join probe")
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the peer to accept the join probe")
+	}
+}